@@ -0,0 +1,201 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	// credentialExecTimeout bounds how long a credential_exec command may run.
+	credentialExecTimeout = time.Minute
+
+	// credentialExecWaitDelay bounds how long to wait for stdout to close once
+	// the command exits or is killed, as background processes it started may
+	// still hold the pipe open.
+	credentialExecWaitDelay = 500 * time.Millisecond
+)
+
+// credentialExecModel maps the credential_exec block schema data.
+type credentialExecModel struct {
+	Command types.String `tfsdk:"command"`
+	Args    types.List   `tfsdk:"args"`
+	Env     types.Map    `tfsdk:"env"`
+}
+
+// execCredentials is the JSON document a credential_exec command writes to
+// stdout. ExpiresAt is optional; when unset the credentials are reused for
+// the life of the provider process. Token is only read so Configure can warn
+// that it is ignored, and other keys are ignored silently.
+type execCredentials struct {
+	Username  string
+	Password  string
+	Token     string
+	ExpiresAt time.Time
+}
+
+// credentialExecCacheKeyFields is encoded to build credentialExecCacheKey.
+type credentialExecCacheKeyFields struct {
+	Command string
+	Args    []string
+	Env     map[string]string
+}
+
+// expired reports whether the credentials have passed their expiry time.
+func (c execCredentials) expired(now time.Time) bool {
+	return !c.ExpiresAt.IsZero() && !now.Before(c.ExpiresAt)
+}
+
+// missingSettingHint returns a sentence for the Missing diagnostics of
+// Configure when the credential_exec command ran but did not return key, or
+// an empty string otherwise. A nil receiver means the command did not run.
+func (c *execCredentials) missingSettingHint(key string) string {
+	if c == nil {
+		return ""
+	}
+
+	var value string
+	switch key {
+	case "username":
+		value = c.Username
+	case "password":
+		value = c.Password
+	default:
+		return ""
+	}
+	if value != "" {
+		return ""
+	}
+
+	return fmt.Sprintf("The credential_exec command ran but did not return a %s. ", key)
+}
+
+// credentialsFromExec returns the credentials emitted by the credential_exec
+// command, reusing the previous result until it expires.
+func (p *snaProvider) credentialsFromExec(ctx context.Context, command string, args []string, env map[string]string) (execCredentials, error) {
+	p.credentialExecMutex.Lock()
+	defer p.credentialExecMutex.Unlock()
+
+	key := credentialExecCacheKey(command, args, env)
+	if p.credentialExecCache != nil && p.credentialExecCacheKey == key && !p.credentialExecCache.expired(time.Now()) {
+		return *p.credentialExecCache, nil
+	}
+
+	credentials, err := runCredentialExec(ctx, command, args, env)
+	if err != nil {
+		return execCredentials{}, err
+	}
+
+	p.credentialExecCache = &credentials
+	p.credentialExecCacheKey = key
+
+	return credentials, nil
+}
+
+// runCredentialExec runs the command and parses its stdout. Stderr is
+// discarded, and neither stream is included in returned errors as either may
+// carry secrets.
+func runCredentialExec(ctx context.Context, command string, args []string, env map[string]string) (execCredentials, error) {
+	commandPath, err := exec.LookPath(command)
+	if err != nil {
+		return execCredentials{}, fmt.Errorf("command %q not found: %w", command, err)
+	}
+
+	// The caller's context may carry a shorter deadline than ours, so report
+	// whichever applies.
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, credentialExecTimeout)
+	defer cancel()
+	deadline, _ := ctx.Deadline()
+
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, commandPath, args...)
+	cmd.Env = os.Environ()
+	for _, name := range sortedKeys(env) {
+		cmd.Env = append(cmd.Env, name+"="+env[name])
+	}
+	cmd.Stdout = &stdout
+	cmd.WaitDelay = credentialExecWaitDelay
+
+	// ErrWaitDelay alone means the command exited successfully but left a
+	// background process holding stdout, so its output is complete.
+	if err := cmd.Run(); err != nil && !errors.Is(err, exec.ErrWaitDelay) {
+		switch {
+		case errors.Is(ctx.Err(), context.DeadlineExceeded):
+			return execCredentials{}, fmt.Errorf("command %q did not finish within %s", command, deadline.Sub(start).Round(time.Millisecond))
+		case ctx.Err() != nil:
+			return execCredentials{}, fmt.Errorf("command %q was cancelled: %w", command, ctx.Err())
+		}
+		return execCredentials{}, fmt.Errorf("command %q failed: %w", command, err)
+	}
+
+	credentials, err := parseExecCredentials(stdout.Bytes())
+	if err != nil {
+		return execCredentials{}, fmt.Errorf("command %q %w", command, err)
+	}
+
+	if credentials.Username == "" && credentials.Password == "" {
+		return execCredentials{}, fmt.Errorf("command %q output contains neither a username nor a password", command)
+	}
+
+	return credentials, nil
+}
+
+// parseExecCredentials decodes credential_exec output. Each known key is
+// decoded separately so an error can name the key without including its value.
+func parseExecCredentials(output []byte) (execCredentials, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(output, &fields); err != nil {
+		return execCredentials{}, errors.New("output is not a valid credentials JSON object")
+	}
+
+	var credentials execCredentials
+	for _, field := range []struct {
+		key      string
+		target   any
+		expected string
+	}{
+		{"username", &credentials.Username, "a string"},
+		{"password", &credentials.Password, "a string"},
+		{"token", &credentials.Token, "a string"},
+		{"expires_at", &credentials.ExpiresAt, "an RFC 3339 timestamp string"},
+	} {
+		raw, ok := fields[field.key]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(raw, field.target); err != nil {
+			return execCredentials{}, fmt.Errorf("output has an invalid %s value, expected %s", field.key, field.expected)
+		}
+	}
+
+	return credentials, nil
+}
+
+// credentialExecCacheKey identifies a credential_exec invocation so a changed
+// configuration does not reuse credentials from a different command.
+func credentialExecCacheKey(command string, args []string, env map[string]string) string {
+	// Marshalling these types cannot fail, and map keys are encoded sorted.
+	key, _ := json.Marshal(credentialExecCacheKeyFields{Command: command, Args: args, Env: env})
+
+	return string(key)
+}
+
+// sortedKeys returns the keys of m in lexical order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
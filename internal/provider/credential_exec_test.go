@@ -0,0 +1,320 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// writeCredentialCommand writes a stub credential command that records each
+// invocation in a "calls" file beside it and runs script.
+func writeCredentialCommand(t *testing.T, script string) (command string, calls string) {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("stub credential command requires a POSIX shell")
+	}
+
+	dir := t.TempDir()
+	command = filepath.Join(dir, "sna-credentials")
+	calls = filepath.Join(dir, "calls")
+	content := "#!/bin/sh\necho x >> '" + calls + "'\n" + script + "\n"
+	if err := os.WriteFile(command, []byte(content), 0o700); err != nil {
+		t.Fatalf("unable to write credential command: %s", err)
+	}
+
+	return command, calls
+}
+
+func countCalls(t *testing.T, calls string) int {
+	t.Helper()
+
+	content, err := os.ReadFile(calls)
+	if err != nil {
+		t.Fatalf("unable to read calls file: %s", err)
+	}
+
+	return strings.Count(string(content), "x")
+}
+
+func TestRunCredentialExec(t *testing.T) {
+	command, _ := writeCredentialCommand(t, `printf '{"username": "%s", "password": "%s"}' "$1" "$SNA_TEST_PASSWORD"`)
+
+	credentials, err := runCredentialExec(context.Background(), command, []string{"admin"}, map[string]string{"SNA_TEST_PASSWORD": "secret"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if credentials.Username != "admin" || credentials.Password != "secret" {
+		t.Errorf("expected admin/secret, got %s/%s", credentials.Username, credentials.Password)
+	}
+}
+
+func TestRunCredentialExecErrors(t *testing.T) {
+	testCases := map[string]struct {
+		script string
+		err    string
+	}{
+		"invalid json": {
+			script: `echo 'password=leaked-secret'`,
+			err:    "output is not a valid credentials JSON object",
+		},
+		"trailing data": {
+			script: `echo '{"username": "admin", "password": "secret"} leaked-secret'`,
+			err:    "output is not a valid credentials JSON object",
+		},
+		"invalid expiry": {
+			script: `echo '{"username": "admin", "password": "secret", "expires_at": "leaked-secret"}'`,
+			err:    "output has an invalid expires_at value, expected an RFC 3339 timestamp string",
+		},
+		"invalid username type": {
+			script: `echo '{"username": ["leaked-secret"], "password": "secret"}'`,
+			err:    "output has an invalid username value, expected a string",
+		},
+		"no credentials": {
+			script: `echo '{}'`,
+			err:    "output contains neither a username nor a password",
+		},
+		"command failure": {
+			script: `echo 'leaked-secret' >&2; exit 3`,
+			err:    "exit status 3",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			command, _ := writeCredentialCommand(t, testCase.script)
+
+			_, err := runCredentialExec(context.Background(), command, nil, nil)
+			if err == nil {
+				t.Fatal("expected error, got none")
+			}
+			if !strings.Contains(err.Error(), testCase.err) {
+				t.Errorf("expected error containing %q, got %q", testCase.err, err)
+			}
+			if strings.Contains(err.Error(), "leaked-secret") {
+				t.Errorf("error exposes command output: %q", err)
+			}
+		})
+	}
+}
+
+func TestRunCredentialExecIgnoresExtraFields(t *testing.T) {
+	command, _ := writeCredentialCommand(t, `echo '{"username": "admin", "password": "secret", "token": "abc", "lease_id": "vault/123"}'`)
+
+	credentials, err := runCredentialExec(context.Background(), command, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if credentials.Username != "admin" || credentials.Password != "secret" || credentials.Token != "abc" {
+		t.Errorf("expected admin/secret/abc, got %s/%s/%s", credentials.Username, credentials.Password, credentials.Token)
+	}
+}
+
+func TestRunCredentialExecDeadline(t *testing.T) {
+	// The shell's sleep child keeps stdout open after the shell is killed.
+	command, _ := writeCredentialCommand(t, `sleep 5`)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := runCredentialExec(ctx, command, nil, nil)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("expected command to be stopped near its deadline, took %s", elapsed)
+	}
+	if err == nil || !strings.Contains(err.Error(), "did not finish within 200ms") {
+		t.Errorf("expected deadline error, got %v", err)
+	}
+}
+
+func TestRunCredentialExecBackgroundProcess(t *testing.T) {
+	command, _ := writeCredentialCommand(t, `(sleep 5) &
+echo '{"username": "admin", "password": "secret"}'`)
+
+	start := time.Now()
+	credentials, err := runCredentialExec(context.Background(), command, nil, nil)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("expected lingering background process to be ignored, took %s", elapsed)
+	}
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if credentials.Username != "admin" || credentials.Password != "secret" {
+		t.Errorf("expected admin/secret, got %s/%s", credentials.Username, credentials.Password)
+	}
+}
+
+func TestRunCredentialExecMissingCommand(t *testing.T) {
+	_, err := runCredentialExec(context.Background(), filepath.Join(t.TempDir(), "missing"), nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected command not found error, got %v", err)
+	}
+}
+
+func TestCredentialsFromExecCaching(t *testing.T) {
+	command, calls := writeCredentialCommand(t, `echo '{"username": "admin", "password": "secret"}'`)
+	p := &snaProvider{}
+
+	for i := 0; i < 2; i++ {
+		if _, err := p.credentialsFromExec(context.Background(), command, nil, nil); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if got := countCalls(t, calls); got != 1 {
+		t.Errorf("expected cached credentials to be reused, command ran %d times", got)
+	}
+
+	// A different invocation must not reuse the cached credentials.
+	if _, err := p.credentialsFromExec(context.Background(), command, []string{"prod"}, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := countCalls(t, calls); got != 2 {
+		t.Errorf("expected changed arguments to re-run the command, command ran %d times", got)
+	}
+}
+
+func TestCredentialExecCacheKey(t *testing.T) {
+	// An argument that looks like an environment variable must not collide
+	// with the environment variable itself.
+	argsKey := credentialExecCacheKey("sna-credentials", []string{"A=B"}, nil)
+	envKey := credentialExecCacheKey("sna-credentials", nil, map[string]string{"A": "B"})
+	if argsKey == envKey {
+		t.Errorf("expected distinct cache keys, both were %q", argsKey)
+	}
+}
+
+func TestCredentialsFromExecExpiry(t *testing.T) {
+	expiresAt := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	command, calls := writeCredentialCommand(t, `echo '{"username": "admin", "password": "secret", "expires_at": "`+expiresAt+`"}'`)
+	p := &snaProvider{}
+
+	for i := 0; i < 2; i++ {
+		if _, err := p.credentialsFromExec(context.Background(), command, nil, nil); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if got := countCalls(t, calls); got != 2 {
+		t.Errorf("expected expired credentials to re-run the command, command ran %d times", got)
+	}
+}
+
+// credentialExecValue builds a credential_exec block value for Configure.
+func credentialExecValue(command string, args []tftypes.Value, env map[string]tftypes.Value) tftypes.Value {
+	return tftypes.NewValue(
+		tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+			"command": tftypes.String,
+			"args":    tftypes.List{ElementType: tftypes.String},
+			"env":     tftypes.Map{ElementType: tftypes.String},
+		}},
+		map[string]tftypes.Value{
+			"command": tftypes.NewValue(tftypes.String, command),
+			"args":    tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, args),
+			"env":     tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, env),
+		},
+	)
+}
+
+func TestConfigureCredentialExec(t *testing.T) {
+	t.Setenv("SNA_PROFILE", "")
+	t.Setenv("SNA_HOST", "")
+	t.Setenv("SNA_USERNAME", "")
+	t.Setenv("SNA_PASSWORD", "")
+
+	usernameOnly, _ := writeCredentialCommand(t, `echo '{"username": "admin"}'`)
+	missingCommand := filepath.Join(t.TempDir(), "missing")
+
+	testCases := map[string]struct {
+		config  map[string]tftypes.Value
+		summary string
+		detail  string
+		path    string
+		absent  string
+	}{
+		"unknown argument": {
+			config: map[string]tftypes.Value{
+				"credential_exec": credentialExecValue(usernameOnly, []tftypes.Value{
+					tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+				}, nil),
+			},
+			summary: "Unknown Secure Network Analytics Credential Command",
+			path:    "credential_exec.args[0]",
+		},
+		"null argument": {
+			config: map[string]tftypes.Value{
+				"credential_exec": credentialExecValue(usernameOnly, []tftypes.Value{
+					tftypes.NewValue(tftypes.String, nil),
+				}, nil),
+			},
+			summary: "Null Secure Network Analytics Credential Command Value",
+			path:    "credential_exec.args[0]",
+			absent:  "Value Conversion Error",
+		},
+		"null environment variable": {
+			config: map[string]tftypes.Value{
+				"credential_exec": credentialExecValue(usernameOnly, nil, map[string]tftypes.Value{
+					"X": tftypes.NewValue(tftypes.String, nil),
+				}),
+			},
+			summary: "Null Secure Network Analytics Credential Command Value",
+			path:    `credential_exec.env["X"]`,
+			absent:  "Value Conversion Error",
+		},
+		// The command does not exist, so reaching it would add an Unable to
+		// Obtain diagnostic; only the missing host should be reported.
+		"explicit credentials skip command": {
+			config: map[string]tftypes.Value{
+				"username":        tftypes.NewValue(tftypes.String, "admin"),
+				"password":        tftypes.NewValue(tftypes.String, "secret"),
+				"credential_exec": credentialExecValue(missingCommand, nil, nil),
+			},
+			summary: "Missing Secure Network Analytics API Host",
+			absent:  "Unable to Obtain Secure Network Analytics Credentials",
+		},
+		"command omits password": {
+			config: map[string]tftypes.Value{
+				"host":            tftypes.NewValue(tftypes.String, "https://smc.example.com"),
+				"credential_exec": credentialExecValue(usernameOnly, nil, nil),
+			},
+			summary: "Missing Secure Network Analytics API Password",
+			detail:  "The credential_exec command ran but did not return a password.",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			resp := configureTestProvider(t, New("test")(), testCase.config)
+
+			var found bool
+			for _, diagnostic := range resp.Diagnostics.Errors() {
+				if diagnostic.Summary() == testCase.absent {
+					t.Errorf("unexpected %q diagnostic: %s", testCase.absent, diagnostic.Detail())
+				}
+				if diagnostic.Summary() != testCase.summary {
+					continue
+				}
+				found = true
+				if !strings.Contains(diagnostic.Detail(), testCase.detail) {
+					t.Errorf("expected detail containing %q, got %q", testCase.detail, diagnostic.Detail())
+				}
+				if testCase.path != "" {
+					withPath, ok := diagnostic.(interface{ Path() path.Path })
+					if !ok || withPath.Path().String() != testCase.path {
+						t.Errorf("expected diagnostic at %s, got %v", testCase.path, diagnostic)
+					}
+				}
+			}
+			if !found {
+				t.Errorf("expected %q diagnostic, got %v", testCase.summary, resp.Diagnostics)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package provider
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// snaProfile holds the connection settings of a named profile. Name and Path
+// are empty when no profile is selected.
+type snaProfile struct {
+	Name     string
+	Path     string
+	Host     string
+	Username string
+	Password string
+}
+
+// missingSettingHint returns a sentence for the Missing diagnostics of
+// Configure when the selected profile was read but does not set key, or an
+// empty string otherwise.
+func (p snaProfile) missingSettingHint(key string) string {
+	if p.Name == "" {
+		return ""
+	}
+
+	var value string
+	switch key {
+	case "host":
+		value = p.Host
+	case "username":
+		value = p.Username
+	case "password":
+		value = p.Password
+	}
+	if value != "" {
+		return ""
+	}
+
+	return fmt.Sprintf("The %q profile selected by SNA_PROFILE was read from %s but does not set %s. ", p.Name, p.Path, key)
+}
+
+// profilesFilePath returns the location of the profiles file, which may be
+// overridden with the SNA_CONFIG_FILE environment variable.
+func profilesFilePath() (string, error) {
+	if path := os.Getenv("SNA_CONFIG_FILE"); path != "" {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to determine home directory: %w", err)
+	}
+
+	return filepath.Join(home, ".sna", "config"), nil
+}
+
+// loadProfile reads the named profile from the profiles file at path.
+//
+// The file uses an INI-style layout, one section per profile:
+//
+//	[prod]
+//	host     = https://smc.example.com
+//	username = admin
+//	password = secret
+func loadProfile(path, name string) (snaProfile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return snaProfile{}, fmt.Errorf("unable to open profiles file: %w", err)
+	}
+	defer file.Close()
+
+	profiles := map[string]*snaProfile{}
+	var current *snaProfile
+
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return snaProfile{}, fmt.Errorf("%s:%d: malformed profile header %q", path, lineNumber, line)
+			}
+			section := strings.TrimSpace(line[1 : len(line)-1])
+			if section == "" {
+				return snaProfile{}, fmt.Errorf("%s:%d: empty profile name", path, lineNumber)
+			}
+			if _, ok := profiles[section]; ok {
+				return snaProfile{}, fmt.Errorf("%s:%d: duplicate profile %q", path, lineNumber, section)
+			}
+			current = &snaProfile{Name: section, Path: path}
+			profiles[section] = current
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return snaProfile{}, fmt.Errorf("%s:%d: expected key = value, got %q", path, lineNumber, line)
+		}
+		if current == nil {
+			return snaProfile{}, fmt.Errorf("%s:%d: setting outside of a profile section", path, lineNumber)
+		}
+
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		switch key {
+		case "host":
+			current.Host = value
+		case "username":
+			current.Username = value
+		case "password":
+			current.Password = value
+		default:
+			return snaProfile{}, fmt.Errorf("%s:%d: unknown setting %q", path, lineNumber, key)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return snaProfile{}, fmt.Errorf("unable to read profiles file: %w", err)
+	}
+
+	profile, ok := profiles[name]
+	if !ok {
+		return snaProfile{}, fmt.Errorf("profile %q not found in %s", name, path)
+	}
+
+	return *profile, nil
+}
+
+// selectedProfile loads the profile named by SNA_PROFILE, returning an empty
+// profile when the variable is unset.
+func selectedProfile() (snaProfile, error) {
+	name := os.Getenv("SNA_PROFILE")
+	if name == "" {
+		return snaProfile{}, nil
+	}
+
+	path, err := profilesFilePath()
+	if err != nil {
+		return snaProfile{}, err
+	}
+
+	return loadProfile(path, name)
+}
+
+// providerDefaults returns the connection settings used when the provider
+// configuration leaves them unset. Environment variables take precedence over
+// the profile.
+func providerDefaults(profile snaProfile) snaProfile {
+	defaults := profile
+
+	if host := os.Getenv("SNA_HOST"); host != "" {
+		defaults.Host = host
+	}
+
+	if username := os.Getenv("SNA_USERNAME"); username != "" {
+		defaults.Username = username
+	}
+
+	if password := os.Getenv("SNA_PASSWORD"); password != "" {
+		defaults.Password = password
+	}
+
+	return defaults
+}
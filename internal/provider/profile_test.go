@@ -0,0 +1,200 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+const testProfilesFile = `
+# Shared appliance profiles
+[dev]
+host     = https://smc-dev.example.com
+username = dev-admin
+password = dev-secret
+
+[prod]
+host     = https://smc.example.com
+username = admin
+password = prod-secret
+`
+
+func writeProfilesFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("unable to write profiles file: %s", err)
+	}
+
+	return path
+}
+
+func TestLoadProfile(t *testing.T) {
+	path := writeProfilesFile(t, testProfilesFile)
+
+	profile, err := loadProfile(path, "prod")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := snaProfile{
+		Name:     "prod",
+		Path:     path,
+		Host:     "https://smc.example.com",
+		Username: "admin",
+		Password: "prod-secret",
+	}
+	if profile != expected {
+		t.Errorf("expected %+v, got %+v", expected, profile)
+	}
+}
+
+func TestLoadProfileErrors(t *testing.T) {
+	testCases := map[string]struct {
+		content string
+		profile string
+		err     string
+	}{
+		"missing profile": {
+			content: testProfilesFile,
+			profile: "stage",
+			err:     `profile "stage" not found`,
+		},
+		"unknown setting": {
+			content: "[dev]\nhostname = smc\n",
+			profile: "dev",
+			err:     `:2: unknown setting "hostname"`,
+		},
+		"setting outside section": {
+			content: "host = smc\n",
+			profile: "dev",
+			err:     ":1: setting outside of a profile section",
+		},
+		"malformed line": {
+			content: "[dev]\nhost\n",
+			profile: "dev",
+			err:     ":2: expected key = value",
+		},
+		"malformed header": {
+			content: "[dev\n",
+			profile: "dev",
+			err:     ":1: malformed profile header",
+		},
+		"duplicate profile": {
+			content: "[dev]\n[dev]\n",
+			profile: "dev",
+			err:     `:2: duplicate profile "dev"`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			path := writeProfilesFile(t, testCase.content)
+
+			_, err := loadProfile(path, testCase.profile)
+			if err == nil {
+				t.Fatal("expected error, got none")
+			}
+			if !strings.Contains(err.Error(), testCase.err) {
+				t.Errorf("expected error containing %q, got %q", testCase.err, err)
+			}
+		})
+	}
+}
+
+func TestProviderDefaults(t *testing.T) {
+	path := writeProfilesFile(t, testProfilesFile)
+	t.Setenv("SNA_CONFIG_FILE", path)
+	t.Setenv("SNA_PROFILE", "dev")
+	t.Setenv("SNA_HOST", "")
+	t.Setenv("SNA_USERNAME", "env-admin")
+	t.Setenv("SNA_PASSWORD", "")
+
+	profile, err := selectedProfile()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// Environment variables override the profile, which fills in the rest.
+	defaults := providerDefaults(profile)
+	expected := snaProfile{
+		Name:     "dev",
+		Path:     path,
+		Host:     "https://smc-dev.example.com",
+		Username: "env-admin",
+		Password: "dev-secret",
+	}
+	if defaults != expected {
+		t.Errorf("expected %+v, got %+v", expected, defaults)
+	}
+}
+
+func TestSelectedProfileMissingProfilesFile(t *testing.T) {
+	t.Setenv("SNA_CONFIG_FILE", filepath.Join(t.TempDir(), "missing"))
+	t.Setenv("SNA_PROFILE", "dev")
+
+	if _, err := selectedProfile(); err == nil {
+		t.Fatal("expected error, got none")
+	}
+}
+
+func TestConfigureProfilePrecedence(t *testing.T) {
+	path := writeProfilesFile(t, testProfilesFile+"\n[partial]\nhost = https://smc.example.com\nusername = admin\n")
+	t.Setenv("SNA_CONFIG_FILE", path)
+	t.Setenv("SNA_HOST", "")
+	t.Setenv("SNA_USERNAME", "")
+	t.Setenv("SNA_PASSWORD", "")
+
+	testCases := map[string]struct {
+		profile  string
+		config   map[string]tftypes.Value
+		summary  string
+		contains string
+		excludes string
+	}{
+		// An explicit empty host in the configuration replaces the host from
+		// the profile, so the provider reports it missing.
+		"configuration overrides profile": {
+			profile: "dev",
+			config: map[string]tftypes.Value{
+				"host": tftypes.NewValue(tftypes.String, ""),
+			},
+			summary:  "Missing Secure Network Analytics API Host",
+			excludes: "SNA_PROFILE was read",
+		},
+		"profile without password": {
+			profile:  "partial",
+			summary:  "Missing Secure Network Analytics API Password",
+			contains: `The "partial" profile selected by SNA_PROFILE was read from ` + path + " but does not set password.",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("SNA_PROFILE", testCase.profile)
+
+			resp := configureTestProvider(t, New("test")(), testCase.config)
+
+			var found bool
+			for _, diagnostic := range resp.Diagnostics.Errors() {
+				if diagnostic.Summary() != testCase.summary {
+					continue
+				}
+				found = true
+				if !strings.Contains(diagnostic.Detail(), testCase.contains) {
+					t.Errorf("expected detail containing %q, got %q", testCase.contains, diagnostic.Detail())
+				}
+				if testCase.excludes != "" && strings.Contains(diagnostic.Detail(), testCase.excludes) {
+					t.Errorf("expected detail without %q, got %q", testCase.excludes, diagnostic.Detail())
+				}
+			}
+			if !found {
+				t.Errorf("expected %q diagnostic, got %v", testCase.summary, resp.Diagnostics)
+			}
+		})
+	}
+}
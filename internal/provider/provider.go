@@ -3,7 +3,7 @@ package provider
 import (
 	"context"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
-	"os"
+	"sync"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -33,13 +33,20 @@ type snaProvider struct {
 	// provider is built and ran locally, and "test" when running acceptance
 	// testing.
 	version string
+
+	// credentialExecMutex guards the cached credential_exec result, which is
+	// reused across Configure calls until it expires.
+	credentialExecMutex    sync.Mutex
+	credentialExecCache    *execCredentials
+	credentialExecCacheKey string
 }
 
 // snaProviderModel maps provider schema data to a Go type.
 type snaProviderModel struct {
-	Host     types.String `tfsdk:"host"`
-	Username types.String `tfsdk:"username"`
-	Password types.String `tfsdk:"password"`
+	Host           types.String         `tfsdk:"host"`
+	Username       types.String         `tfsdk:"username"`
+	Password       types.String         `tfsdk:"password"`
+	CredentialExec *credentialExecModel `tfsdk:"credential_exec"`
 }
 
 // Metadata returns the provider type name.
@@ -54,19 +61,41 @@ func (p *snaProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp *
 		Description: "Interact with Secure Network Analytics.",
 		Attributes: map[string]schema.Attribute{
 			"host": schema.StringAttribute{
-				Description: "URI for Secure Network Analytics API. May also be provided via SNA_HOST environment variable.",
+				Description: "URI for Secure Network Analytics API. May also be provided via SNA_HOST environment variable or an SNA_PROFILE profile.",
 				Optional:    true,
 			},
 			"username": schema.StringAttribute{
-				Description: "Username for Secure Network Analytics API. May also be provided via SNA_USERNAME environment variable.",
+				Description: "Username for Secure Network Analytics API. May also be provided via SNA_USERNAME environment variable or an SNA_PROFILE profile.",
 				Optional:    true,
 			},
 			"password": schema.StringAttribute{
-				Description: "Password for Secure Network Analytics API. May also be provided via SNA_PASSWORD environment variable.",
+				Description: "Password for Secure Network Analytics API. May also be provided via SNA_PASSWORD environment variable or an SNA_PROFILE profile.",
 				Optional:    true,
 				Sensitive:   true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"credential_exec": schema.SingleNestedBlock{
+				Description: "External command that writes Secure Network Analytics credentials to stdout as a JSON object with username, password, and an optional RFC 3339 expires_at. " +
+					"Credentials are reused until they expire. Explicit username and password values take precedence, and the command is not run when both are set.",
+				Attributes: map[string]schema.Attribute{
+					"command": schema.StringAttribute{
+						Description: "Command to run, either a path or a name found on PATH.",
+						Optional:    true,
+					},
+					"args": schema.ListAttribute{
+						Description: "Arguments passed to the command.",
+						ElementType: types.StringType,
+						Optional:    true,
+					},
+					"env": schema.MapAttribute{
+						Description: "Environment variables set for the command in addition to the provider's environment.",
+						ElementType: types.StringType,
+						Optional:    true,
+					},
+				},
+			},
+		},
 	}
 }
 
@@ -110,16 +139,134 @@ func (p *snaProvider) Configure(ctx context.Context, req provider.ConfigureReque
 		)
 	}
 
+	if config.CredentialExec != nil {
+		var unknown []path.Path
+		if config.CredentialExec.Command.IsUnknown() {
+			unknown = append(unknown, path.Root("credential_exec").AtName("command"))
+		}
+
+		if config.CredentialExec.Args.IsUnknown() {
+			unknown = append(unknown, path.Root("credential_exec").AtName("args"))
+		}
+		var null []path.Path
+		for index, arg := range config.CredentialExec.Args.Elements() {
+			if arg.IsUnknown() {
+				unknown = append(unknown, path.Root("credential_exec").AtName("args").AtListIndex(index))
+			}
+			if arg.IsNull() {
+				null = append(null, path.Root("credential_exec").AtName("args").AtListIndex(index))
+			}
+		}
+
+		if config.CredentialExec.Env.IsUnknown() {
+			unknown = append(unknown, path.Root("credential_exec").AtName("env"))
+		}
+		for name, value := range config.CredentialExec.Env.Elements() {
+			if value.IsUnknown() {
+				unknown = append(unknown, path.Root("credential_exec").AtName("env").AtMapKey(name))
+			}
+			if value.IsNull() {
+				null = append(null, path.Root("credential_exec").AtName("env").AtMapKey(name))
+			}
+		}
+
+		for _, unknownPath := range unknown {
+			resp.Diagnostics.AddAttributeError(
+				unknownPath,
+				"Unknown Secure Network Analytics Credential Command",
+				"The provider cannot create the Secure Network Analytics API client as there is an unknown configuration value for the Secure Network Analytics credential command. "+
+					"Either target apply the source of the value first or set the value statically in the configuration.",
+			)
+		}
+
+		for _, nullPath := range null {
+			resp.Diagnostics.AddAttributeError(
+				nullPath,
+				"Null Secure Network Analytics Credential Command Value",
+				"The provider cannot run the Secure Network Analytics credential command as there is a null value in its args or env. "+
+					"Set the value to a string or remove it from the configuration.",
+			)
+		}
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Default values to environment variables, but override
-	// with Terraform configuration value if set.
+	// Default values to environment variables or the selected profile,
+	// but override with Terraform configuration value if set.
 
-	host := os.Getenv("SNA_HOST")
-	username := os.Getenv("SNA_USERNAME")
-	password := os.Getenv("SNA_PASSWORD")
+	profile, err := selectedProfile()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Secure Network Analytics Profile",
+			"The provider cannot load the Secure Network Analytics profile selected by the SNA_PROFILE environment variable. "+
+				"Ensure the profile exists in the profiles file (~/.sna/config, or the path in the SNA_CONFIG_FILE environment variable) and that the file is well formed.\n\n"+
+				"Profile Error: "+err.Error(),
+		)
+		return
+	}
+
+	defaults := providerDefaults(profile)
+	host := defaults.Host
+	username := defaults.Username
+	password := defaults.Password
+
+	// Explicit username and password values always win, so a fully explicit
+	// configuration never waits on the credential command.
+	var execResult *execCredentials
+	if config.CredentialExec != nil && (config.Username.IsNull() || config.Password.IsNull()) {
+		if config.CredentialExec.Command.ValueString() == "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("credential_exec").AtName("command"),
+				"Missing Secure Network Analytics Credential Command",
+				"The provider cannot run the Secure Network Analytics credential command as there is a missing or empty value for the command. "+
+					"Set the command value in the credential_exec block or remove the block.",
+			)
+			return
+		}
+
+		var args []string
+		resp.Diagnostics.Append(config.CredentialExec.Args.ElementsAs(ctx, &args, false)...)
+		env := map[string]string{}
+		resp.Diagnostics.Append(config.CredentialExec.Env.ElementsAs(ctx, &env, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		tflog.Debug(ctx, "Running Secure Network Analytics credential command", map[string]any{"command": config.CredentialExec.Command.ValueString()})
+
+		credentials, err := p.credentialsFromExec(ctx, config.CredentialExec.Command.ValueString(), args, env)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("credential_exec"),
+				"Unable to Obtain Secure Network Analytics Credentials",
+				"The provider cannot obtain Secure Network Analytics credentials from the credential command. "+
+					"Ensure the command exists, exits successfully, and writes a JSON object with username and password to stdout.\n\n"+
+					"Credential Command Error: "+err.Error(),
+			)
+			return
+		}
+
+		execResult = &credentials
+
+		if credentials.Token != "" {
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("credential_exec"),
+				"Secure Network Analytics Credential Token Ignored",
+				"The credential command returned a token, but the Secure Network Analytics API client only supports username and password authentication. "+
+					"The token is ignored.",
+			)
+		}
+
+		if credentials.Username != "" {
+			username = credentials.Username
+		}
+
+		if credentials.Password != "" {
+			password = credentials.Password
+		}
+	}
 
 	if !config.Host.IsNull() {
 		host = config.Host.ValueString()
@@ -142,6 +289,7 @@ func (p *snaProvider) Configure(ctx context.Context, req provider.ConfigureReque
 			"Missing Secure Network Analytics API Host",
 			"The provider cannot create the Secure Network Analytics API client as there is a missing or empty value for the Secure Network Analytics API host. "+
 				"Set the host value in the configuration or use the SNA_HOST environment variable. "+
+				profile.missingSettingHint("host")+
 				"If either is already set, ensure the value is not empty.",
 		)
 	}
@@ -152,6 +300,8 @@ func (p *snaProvider) Configure(ctx context.Context, req provider.ConfigureReque
 			"Missing Secure Network Analytics API Username",
 			"The provider cannot create the Secure Network Analytics API client as there is a missing or empty value for the Secure Network Analytics API username. "+
 				"Set the username value in the configuration or use the SNA_USERNAME environment variable. "+
+				profile.missingSettingHint("username")+
+				execResult.missingSettingHint("username")+
 				"If either is already set, ensure the value is not empty.",
 		)
 	}
@@ -162,6 +312,8 @@ func (p *snaProvider) Configure(ctx context.Context, req provider.ConfigureReque
 			"Missing Secure Network Analytics API Password",
 			"The provider cannot create the Secure Network Analytics API client as there is a missing or empty value for the Secure Network Analytics API password. "+
 				"Set the password value in the configuration or use the SNA_PASSWORD environment variable. "+
+				profile.missingSettingHint("password")+
+				execResult.missingSettingHint("password")+
 				"If either is already set, ensure the value is not empty.",
 		)
 	}
@@ -1,8 +1,14 @@
 package provider
 
 import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
 
 const (
@@ -28,3 +34,41 @@ var (
 		"hashicups": providerserver.NewProtocol6WithError(New("test")()),
 	}
 )
+
+// configureTestProvider calls Configure on p with a configuration built from
+// its schema, leaving every attribute or block not in values null.
+func configureTestProvider(t *testing.T, p provider.Provider, values map[string]tftypes.Value) *provider.ConfigureResponse {
+	t.Helper()
+
+	ctx := context.Background()
+
+	schemaResp := &provider.SchemaResponse{}
+	p.Schema(ctx, provider.SchemaRequest{}, schemaResp)
+	if schemaResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected schema diagnostics: %v", schemaResp.Diagnostics)
+	}
+
+	objectType, ok := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	if !ok {
+		t.Fatalf("expected provider schema to be an object type")
+	}
+
+	attributes := map[string]tftypes.Value{}
+	for name, attributeType := range objectType.AttributeTypes {
+		attributes[name] = tftypes.NewValue(attributeType, nil)
+	}
+	for name, value := range values {
+		attributes[name] = value
+	}
+
+	req := provider.ConfigureRequest{
+		Config: tfsdk.Config{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(objectType, attributes),
+		},
+	}
+	resp := &provider.ConfigureResponse{}
+	p.Configure(ctx, req, resp)
+
+	return resp
+}